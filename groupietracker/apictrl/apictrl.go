@@ -0,0 +1,203 @@
+// Package apictrl exposes the groupietracker data as a JSON API for
+// programmatic clients, mirroring the pages served by controller.
+package apictrl
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"groupietracker/controller"
+)
+
+const pageSize = 9
+
+type paginationMeta struct {
+	Total int `json:"total"`
+	Page  int `json:"page"`
+	Next  int `json:"next,omitempty"`
+	Prev  int `json:"prev,omitempty"`
+}
+
+type matchesResponse struct {
+	Matches    []controller.Match `json:"matches"`
+	Pagination paginationMeta     `json:"pagination"`
+}
+
+// withCORS wraps a handler with permissive CORS headers so browser-based
+// and script clients can call the API from any origin.
+func withCORS(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RegisterRoutes mounts the JSON API and the OpenSearch descriptor on mux.
+func RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/matches", withCORS(MatchesHandler))
+	mux.HandleFunc("/api/v1/matches/", withCORS(MatchHandler))
+	mux.HandleFunc("/api/v1/favorites", withCORS(FavoritesHandler))
+	mux.HandleFunc("/api/v1/categories", withCORS(CategoriesHandler))
+	mux.HandleFunc("/opensearch.xml", OpenSearchHandler)
+}
+
+// MatchesHandler serves GET /api/v1/matches, accepting the same q/category/
+// source/page query parameters as CollectionHandler, returning pagination
+// metadata mirroring what paginate computes for the HTML pages.
+func MatchesHandler(w http.ResponseWriter, r *http.Request) {
+	source := r.URL.Query().Get("source")
+	matches, err := controller.LoadMatches(w, r, source)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	matches = controller.Filter(matches, r.URL.Query().Get("q"), r.URL.Query().Get("category"))
+
+	page := parsePage(r.URL.Query().Get("page"))
+	paged, _, prev, next := controller.Paginate(matches, page, pageSize)
+
+	if _, notModified := checkETag(w, r, matches, source, page); notModified {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, matchesResponse{
+		Matches: paged,
+		Pagination: paginationMeta{
+			Total: len(matches),
+			Page:  page,
+			Prev:  prev,
+			Next:  next,
+		},
+	})
+}
+
+// MatchHandler serves GET /api/v1/matches/{title}, returning a single match
+// looked up the same way DetailHandler does.
+func MatchHandler(w http.ResponseWriter, r *http.Request) {
+	title := strings.TrimPrefix(r.URL.Path, "/api/v1/matches/")
+	if title == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("titre manquant"))
+		return
+	}
+
+	matches, err := controller.LoadMatches(w, r, "")
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	for _, m := range matches {
+		if m.Title == title {
+			writeJSON(w, http.StatusOK, m)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("match introuvable"))
+}
+
+// FavoritesHandler serves GET /api/v1/favorites for the caller's session.
+func FavoritesHandler(w http.ResponseWriter, r *http.Request) {
+	matches, err := controller.LoadMatches(w, r, "")
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	var favs []controller.Match
+	for _, m := range matches {
+		if m.IsFavorite {
+			favs = append(favs, m)
+		}
+	}
+	writeJSON(w, http.StatusOK, favs)
+}
+
+// CategoriesHandler serves GET /api/v1/categories, the distinct categories
+// currently present across all matches.
+func CategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	matches, err := controller.LoadMatches(w, r, "")
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	var categories []string
+	for _, m := range matches {
+		if m.Category == "" || seen[m.Category] {
+			continue
+		}
+		seen[m.Category] = true
+		categories = append(categories, m.Category)
+	}
+	writeJSON(w, http.StatusOK, categories)
+}
+
+// OpenSearchHandler serves /opensearch.xml so browsers can register the
+// site as a search engine hitting SearchResultsHandler.
+func OpenSearchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/opensearchdescription+xml")
+	fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">
+  <ShortName>Groupie Tracker</ShortName>
+  <Description>Recherche de matchs et vidéos sur Groupie Tracker</Description>
+  <Url type="text/html" template="/search?q={searchTerms}"/>
+  <Url type="application/json" template="/api/v1/matches?q={searchTerms}"/>
+</OpenSearchDescription>`)
+}
+
+func parsePage(raw string) int {
+	if raw == "" {
+		return 1
+	}
+	p, err := strconv.Atoi(raw)
+	if err != nil || p < 1 {
+		return 1
+	}
+	return p
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Printf("apictrl: encodage JSON échoué: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// checkETag derives an ETag from the match set plus the source/page being
+// served and short-circuits with 304 when the client already has it,
+// avoiding a body round-trip. source and page must be folded in: two pages
+// of the same match set would otherwise hash identically and a client
+// caching page 1's ETag would get a 304 (and stale page-1 data) for page 2.
+func checkETag(w http.ResponseWriter, r *http.Request, matches []controller.Match, source string, page int) (string, bool) {
+	h := sha1.New()
+	fmt.Fprintf(h, "source=%s;page=%d;", source, page)
+	for _, m := range matches {
+		fmt.Fprintf(h, "%s|%s;", m.Title, m.DateRaw)
+	}
+	etag := fmt.Sprintf(`"%x"`, h.Sum(nil))
+	w.Header().Set("ETag", etag)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return etag, true
+	}
+	return etag, false
+}
@@ -0,0 +1,72 @@
+package apictrl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"groupietracker/controller"
+)
+
+func TestCheckETagDiffersByPage(t *testing.T) {
+	matches := []controller.Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01"}}
+
+	w1 := httptest.NewRecorder()
+	etagPage1, _ := checkETag(w1, httptest.NewRequest(http.MethodGet, "/", nil), matches, "", 1)
+
+	w2 := httptest.NewRecorder()
+	etagPage2, _ := checkETag(w2, httptest.NewRequest(http.MethodGet, "/", nil), matches, "", 2)
+
+	if etagPage1 == etagPage2 {
+		t.Fatalf("want different ETags for different pages of the same match set, got %q for both", etagPage1)
+	}
+}
+
+func TestCheckETagDiffersBySource(t *testing.T) {
+	matches := []controller.Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01"}}
+
+	w1 := httptest.NewRecorder()
+	etagScorebat, _ := checkETag(w1, httptest.NewRequest(http.MethodGet, "/", nil), matches, "scorebat", 1)
+
+	w2 := httptest.NewRecorder()
+	etagFixtures, _ := checkETag(w2, httptest.NewRequest(http.MethodGet, "/", nil), matches, "fixtures", 1)
+
+	if etagScorebat == etagFixtures {
+		t.Fatalf("want different ETags for different sources, got %q for both", etagScorebat)
+	}
+}
+
+func TestCheckETagReturns304WhenIfNoneMatchMatches(t *testing.T) {
+	matches := []controller.Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01"}}
+
+	w1 := httptest.NewRecorder()
+	etag, _ := checkETag(w1, httptest.NewRequest(http.MethodGet, "/", nil), matches, "", 1)
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.Header.Set("If-None-Match", etag)
+	w2 := httptest.NewRecorder()
+
+	_, notModified := checkETag(w2, r2, matches, "", 1)
+	if !notModified {
+		t.Fatal("want a matching If-None-Match to short-circuit as not modified")
+	}
+	if w2.Code != http.StatusNotModified {
+		t.Fatalf("want status %d, got %d", http.StatusNotModified, w2.Code)
+	}
+}
+
+func TestCheckETagMismatchedPageDoesNotShortCircuit(t *testing.T) {
+	matches := []controller.Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01"}}
+
+	w1 := httptest.NewRecorder()
+	etag, _ := checkETag(w1, httptest.NewRequest(http.MethodGet, "/", nil), matches, "", 1)
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.Header.Set("If-None-Match", etag)
+	w2 := httptest.NewRecorder()
+
+	_, notModified := checkETag(w2, r2, matches, "", 2)
+	if notModified {
+		t.Fatal("want page 1's ETag to not satisfy a page 2 request")
+	}
+}
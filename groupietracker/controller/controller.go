@@ -1,27 +1,67 @@
 package controller
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/rand"
+	"fmt"
 	"html/template"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"groupietracker/localindex"
+	"groupietracker/provider"
+	"groupietracker/reqctx"
+	"groupietracker/store"
+	"groupietracker/useragent"
 )
 
 const (
-	scoreBatURL = "https://www.scorebat.com/video-api/v3/"
-	pageSize    = 9
-	cacheTTL    = 2 * time.Minute
-	cookieName  = "favorites"
+	pageSize          = 9
+	cacheTTL          = 2 * time.Minute
+	sessionCookieName = "gt_session"
+	favoritesDBPath   = "data/favorites.json"
+	fixturePath       = "data/fixtures.json"
+	localMediaDir     = "data/local-media"
 )
 
-type apiResponse struct {
-	Response []Match `json:"response"`
+// favStore is the process-wide favorites persistence. It replaces the old
+// cookie-only storage, which was capped by the browser's ~4KB cookie limit
+// and lost everything when the user cleared cookies.
+var favStore store.FavoritesStore
+
+func init() {
+	s, err := store.NewJSONFavoritesStore(favoritesDBPath)
+	if err != nil {
+		log.Fatalf("impossible d'initialiser le store des favoris: %v", err)
+	}
+	favStore = s
+}
+
+// localIdx overlays locally archived clips/thumbnails on top of the remote
+// matches. It stays nil (the feature is simply off) when localMediaDir
+// doesn't exist, so self-hosters opt in just by creating the directory.
+var localIdx *localindex.Index
+
+func init() {
+	info, err := os.Stat(localMediaDir)
+	if err != nil || !info.IsDir() {
+		return
+	}
+	idx, err := localindex.New(localMediaDir)
+	if err != nil {
+		log.Printf("localindex: désactivé, échec d'initialisation: %v", err)
+		return
+	}
+	localIdx = idx
 }
 
 type Match struct {
@@ -30,15 +70,19 @@ type Match struct {
 	MatchviewURL string `json:"matchviewUrl"`
 	Thumbnail    string `json:"thumbnail"`
 	DateRaw      string `json:"date"`
+	Score        string `json:"score,omitempty"`
 	Videos       []struct {
 		Title string `json:"title"`
 		Embed string `json:"embed"`
 	} `json:"videos"`
 
-	PrettyDate string        `json:"-"`
-	EmbedHTML  template.HTML `json:"-"`
-	IsFavorite bool          `json:"-"`
-	Category   string        `json:"-"`
+	PrettyDate     string        `json:"-"`
+	EmbedHTML      template.HTML `json:"-"`
+	IsFavorite     bool          `json:"-"`
+	Category       string        `json:"-"`
+	Source         string        `json:"source"`
+	LocalVideoPath string        `json:"-"` // set when localIdx has a video hit, served under /media/
+	LocalThumbPath string        `json:"-"`
 }
 
 type listPageData struct {
@@ -47,6 +91,7 @@ type listPageData struct {
 	Query        string
 	Category     string
 	CategoryName string
+	Source       string
 	CurrentPage  int
 	TotalPages   int
 	PrevPage     int
@@ -57,17 +102,33 @@ type detailPageData struct {
 	Match
 }
 
+// aggregator concurrently queries every registered provider (ScoreBat plus
+// whatever else is wired up below) and merges/deduplicates their matches.
+var aggregator = provider.NewAggregator(cacheTTL,
+	provider.NewScoreBatProvider(httpClient),
+	provider.NewFixtureProvider("fixtures", fixturePath),
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func init() {
+	httpClient.Transport = useragent.Wrap(useragent.NewPolicy(&http.Client{Timeout: 10 * time.Second}), nil)
+}
+
+type cacheEntry struct {
+	at      time.Time
+	matches []Match
+}
+
 var (
-	cacheMu     sync.Mutex
-	cachedAt    time.Time
-	cachedMatch []Match
-	httpClient  = &http.Client{Timeout: 10 * time.Second}
+	cacheMu sync.Mutex
+	cache   = map[string]cacheEntry{}
 )
 
 func HomeHandler(w http.ResponseWriter, r *http.Request) {
-	matches, err := loadMatchesWithFavorites(r)
+	matches, err := loadMatchesWithFavorites(w, r, "")
 	if err != nil {
-		serverError(w, err)
+		serverError(w, r, err)
 		return
 	}
 
@@ -79,17 +140,18 @@ func HomeHandler(w http.ResponseWriter, r *http.Request) {
 		Title:   "Dernières vidéos",
 		Matches: matches,
 	}
-	render(w, "home.html", data)
+	render(w, r, "home.html", data)
 }
 
 func CollectionHandler(w http.ResponseWriter, r *http.Request) {
 	query := strings.TrimSpace(r.URL.Query().Get("q"))
 	category := strings.TrimSpace(r.URL.Query().Get("category"))
+	source := strings.TrimSpace(r.URL.Query().Get("source"))
 	page := parsePage(r.URL.Query().Get("page"))
 
-	matches, err := loadMatchesWithFavorites(r)
+	matches, err := loadMatchesWithFavorites(w, r, source)
 	if err != nil {
-		serverError(w, err)
+		serverError(w, r, err)
 		return
 	}
 
@@ -107,6 +169,7 @@ func CollectionHandler(w http.ResponseWriter, r *http.Request) {
 		Title:        title,
 		Matches:      paged,
 		Query:        query,
+		Source:       source,
 		Category:     category,
 		CategoryName: categoryLabel(category),
 		CurrentPage:  page,
@@ -114,7 +177,7 @@ func CollectionHandler(w http.ResponseWriter, r *http.Request) {
 		PrevPage:     prev,
 		NextPage:     next,
 	}
-	render(w, "collection.html", data)
+	render(w, r, "collection.html", data)
 }
 
 func CategoryHandler(w http.ResponseWriter, r *http.Request) {
@@ -143,9 +206,9 @@ func SearchResultsHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func FavoritesPageHandler(w http.ResponseWriter, r *http.Request) {
-	matches, err := loadMatchesWithFavorites(r)
+	matches, err := loadMatchesWithFavorites(w, r, "")
 	if err != nil {
-		serverError(w, err)
+		serverError(w, r, err)
 		return
 	}
 
@@ -160,7 +223,7 @@ func FavoritesPageHandler(w http.ResponseWriter, r *http.Request) {
 		Title:   "Mes Favoris",
 		Matches: favMatches,
 	}
-	render(w, "favorites.html", data)
+	render(w, r, "favorites.html", data)
 }
 
 func DetailHandler(w http.ResponseWriter, r *http.Request) {
@@ -178,9 +241,9 @@ func DetailHandler(w http.ResponseWriter, r *http.Request) {
 		title = strings.TrimSpace(title)
 	}
 
-	matches, err := loadMatchesWithFavorites(r)
+	matches, err := loadMatchesWithFavorites(w, r, "")
 	if err != nil {
-		serverError(w, err)
+		serverError(w, r, err)
 		return
 	}
 
@@ -206,15 +269,17 @@ func DetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Préparer l'embed HTML
-	if len(foundMatch.Videos) > 0 {
+	// Préparer l'embed HTML : un fichier local indexé passe avant l'embed distant.
+	if foundMatch.LocalVideoPath != "" {
+		foundMatch.EmbedHTML = template.HTML(fmt.Sprintf(`<video controls src=%q></video>`, foundMatch.LocalVideoPath))
+	} else if len(foundMatch.Videos) > 0 {
 		foundMatch.EmbedHTML = template.HTML(foundMatch.Videos[0].Embed)
 	} else {
 		log.Printf("Aucune vidéo trouvée pour: %q", foundMatch.Title)
 		foundMatch.EmbedHTML = template.HTML(`<p style="padding: 20px; text-align: center; color: #666;">Aucune vidéo disponible pour ce match.</p>`)
 	}
 
-	render(w, "detail.html", detailPageData{Match: *foundMatch})
+	render(w, r, "detail.html", detailPageData{Match: *foundMatch})
 }
 
 func ToggleFavoriteHandler(w http.ResponseWriter, r *http.Request) {
@@ -224,13 +289,29 @@ func ToggleFavoriteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	favs := getFavorites(r)
-	if favs[title] {
-		delete(favs, title)
+	userID := getUserID(w, r)
+	favs, err := favStore.List(userID)
+	if err != nil {
+		serverError(w, r, err)
+		return
+	}
+
+	isFav := false
+	for _, f := range favs {
+		if f.Title == title {
+			isFav = true
+			break
+		}
+	}
+	if isFav {
+		err = favStore.Remove(userID, title)
 	} else {
-		favs[title] = true
+		err = favStore.Add(userID, title)
+	}
+	if err != nil {
+		serverError(w, r, err)
+		return
 	}
-	saveFavorites(w, favs)
 
 	redirect := r.URL.Query().Get("redirect")
 	if redirect == "" {
@@ -239,55 +320,213 @@ func ToggleFavoriteHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, redirect, http.StatusSeeOther)
 }
 
+// ExportFavoritesHandler downloads the current user's favorites as JSON.
+func ExportFavoritesHandler(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(w, r)
+	raw, err := favStore.Export(userID)
+	if err != nil {
+		serverError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="favorites.json"`)
+	w.Write(raw)
+}
+
+// ImportFavoritesHandler restores favorites from a previously exported JSON
+// payload, merging them into the current user's favorites.
+func ImportFavoritesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "méthode non autorisée", http.StatusMethodNotAllowed)
+		return
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "corps de requête illisible", http.StatusBadRequest)
+		return
+	}
+
+	userID := getUserID(w, r)
+	if err := favStore.Import(userID, raw); err != nil {
+		http.Error(w, "import invalide", http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, r, "/favorites", http.StatusSeeOther)
+}
+
+// SetRecoveryPassphraseHandler attaches a recovery passphrase to the
+// caller's current session, so they can later reattach to their favorites
+// from RecoverFavoritesHandler if they lose the gt_session cookie.
+func SetRecoveryPassphraseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "méthode non autorisée", http.StatusMethodNotAllowed)
+		return
+	}
+
+	passphrase := strings.TrimSpace(r.FormValue("passphrase"))
+	if passphrase == "" {
+		http.Error(w, "passphrase manquante", http.StatusBadRequest)
+		return
+	}
+
+	userID := getUserID(w, r)
+	if err := favStore.SetRecoveryPassphrase(userID, passphrase); err != nil {
+		serverError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/favorites", http.StatusSeeOther)
+}
+
+// RecoverFavoritesHandler reattaches the caller's session to the favorites
+// previously registered under passphrase via SetRecoveryPassphraseHandler,
+// so clearing cookies (or switching devices) doesn't orphan them.
+func RecoverFavoritesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "méthode non autorisée", http.StatusMethodNotAllowed)
+		return
+	}
+
+	passphrase := strings.TrimSpace(r.FormValue("passphrase"))
+	if passphrase == "" {
+		http.Error(w, "passphrase manquante", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := favStore.Recover(passphrase)
+	if err != nil {
+		http.Error(w, "passphrase inconnue", http.StatusNotFound)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    userID,
+		Path:     "/",
+		MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+		HttpOnly: true,
+	})
+	http.Redirect(w, r, "/favorites", http.StatusSeeOther)
+}
+
 func AboutHandler(w http.ResponseWriter, r *http.Request) {
 	data := struct{ Title string }{Title: "À propos"}
-	render(w, "about.html", data)
+	render(w, r, "about.html", data)
+}
+
+// LoadMatches exposes loadMatchesWithFavorites to other packages (the
+// apictrl JSON API), so both the HTML and JSON surfaces share the exact
+// same fetch/favorite logic.
+func LoadMatches(w http.ResponseWriter, r *http.Request, source string) ([]Match, error) {
+	return loadMatchesWithFavorites(w, r, source)
 }
 
-func loadMatchesWithFavorites(r *http.Request) ([]Match, error) {
-	matches, err := fetchMatches()
+// Filter exposes filterMatches to other packages.
+func Filter(matches []Match, query, category string) []Match {
+	return filterMatches(matches, query, category)
+}
+
+// Paginate exposes paginate to other packages.
+func Paginate(matches []Match, page, size int) (paged []Match, totalPages, prev, next int) {
+	return paginate(matches, page, size)
+}
+
+func loadMatchesWithFavorites(w http.ResponseWriter, r *http.Request, source string) ([]Match, error) {
+	matches, err := fetchMatches(source)
 	if err != nil {
 		return nil, err
 	}
 
-	favs := getFavorites(r)
+	userID := getUserID(w, r)
+	favs, err := favStore.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	favTitles := make(map[string]bool, len(favs))
+	for _, f := range favs {
+		favTitles[f.Title] = true
+	}
+
 	for i := range matches {
-		matches[i].IsFavorite = favs[matches[i].Title]
+		matches[i].IsFavorite = favTitles[matches[i].Title]
 	}
+	applyLocalOverlay(matches)
 	return matches, nil
 }
 
-func fetchMatches() ([]Match, error) {
-	cacheMu.Lock()
-	defer cacheMu.Unlock()
+// applyLocalOverlay fills in LocalVideoPath/LocalThumbPath wherever localIdx
+// has a fuzzy hit for a match's title, so DetailHandler and CollectionHandler
+// can prefer self-hosted clips over the remote embed.
+func applyLocalOverlay(matches []Match) {
+	if localIdx == nil {
+		return
+	}
+	for i := range matches {
+		video, thumb := localIdx.Match(matches[i].Title)
+		if video != nil {
+			matches[i].LocalVideoPath = mediaURLFor(video.Path)
+		}
+		if thumb != nil {
+			matches[i].LocalThumbPath = mediaURLFor(thumb.Path)
+		}
+	}
+}
 
-	if time.Since(cachedAt) < cacheTTL && len(cachedMatch) > 0 {
-		return cloneMatches(cachedMatch), nil
+// fetchMatches returns the merged, deduplicated matches across every
+// provider, or just the given one if source is non-empty. Results are
+// cached per source for cacheTTL.
+func fetchMatches(source string) ([]Match, error) {
+	cacheMu.Lock()
+	if e, ok := cache[source]; ok && time.Since(e.at) < cacheTTL && len(e.matches) > 0 {
+		matches := cloneMatches(e.matches)
+		cacheMu.Unlock()
+		return matches, nil
 	}
+	cacheMu.Unlock()
 
-	resp, err := httpClient.Get(scoreBatURL)
+	raw, err := aggregator.Fetch(context.Background(), source)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var apiResp apiResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, err
+	matches := convertMatches(raw)
+	normalizeMatches(matches)
+
+	cacheMu.Lock()
+	cache[source] = cacheEntry{at: time.Now(), matches: matches}
+	cacheMu.Unlock()
+
+	if len(matches) > 0 {
+		log.Printf("Chargé %d matchs (source=%q)", len(matches), source)
 	}
 
-	normalizeMatches(apiResp.Response)
-	cachedMatch = apiResp.Response
-	cachedAt = time.Now()
+	return cloneMatches(matches), nil
+}
 
-	if len(cachedMatch) > 0 {
-		log.Printf("Chargé %d matchs depuis l'API ScoreBat", len(cachedMatch))
-		if len(cachedMatch[0].Videos) > 0 {
-			log.Printf("Exemple de vidéo pour '%s': %d vidéo(s) disponible(s)", cachedMatch[0].Title, len(cachedMatch[0].Videos))
+// convertMatches adapts provider-neutral matches into the controller's own
+// Match type, which additionally carries rendering-only fields.
+func convertMatches(src []provider.Match) []Match {
+	out := make([]Match, len(src))
+	for i, m := range src {
+		out[i].Title = m.Title
+		out[i].Competition = m.Competition
+		out[i].MatchviewURL = m.MatchviewURL
+		out[i].Thumbnail = m.Thumbnail
+		out[i].DateRaw = m.DateRaw
+		out[i].Score = m.Score
+		out[i].Source = m.Source
+		out[i].Videos = make([]struct {
+			Title string `json:"title"`
+			Embed string `json:"embed"`
+		}, len(m.Videos))
+		for j, v := range m.Videos {
+			out[i].Videos[j].Title = v.Title
+			out[i].Videos[j].Embed = v.Embed
 		}
 	}
-
-	return cloneMatches(cachedMatch), nil
+	return out
 }
 
 func normalizeMatches(matches []Match) {
@@ -317,6 +556,10 @@ func cloneMatches(src []Match) []Match {
 	return out
 }
 
+// localCategory is the synthetic ?category= value selecting matches with a
+// local overlay hit instead of filtering on Match.Category.
+const localCategory = "local"
+
 func filterMatches(matches []Match, query, category string) []Match {
 	if query == "" && category == "" {
 		return matches
@@ -332,7 +575,11 @@ func filterMatches(matches []Match, query, category string) []Match {
 				continue
 			}
 		}
-		if c != "" && m.Category != "" && !strings.Contains(m.Category, c) {
+		if c == localCategory {
+			if m.LocalVideoPath == "" {
+				continue
+			}
+		} else if c != "" && m.Category != "" && !strings.Contains(m.Category, c) {
 			continue
 		}
 		filtered = append(filtered, m)
@@ -383,52 +630,50 @@ func parsePage(raw string) int {
 	return p
 }
 
-func getFavorites(r *http.Request) map[string]bool {
-	favs := make(map[string]bool)
-	c, err := r.Cookie(cookieName)
-	if err != nil {
-		return favs
+// getUserID returns the opaque session ID identifying the caller's favorites,
+// creating an anonymous one on first visit. The cookie carries nothing but
+// this ID; the actual favorites live in favStore.
+func getUserID(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(sessionCookieName); err == nil && c.Value != "" {
+		return c.Value
 	}
-	for _, title := range strings.Split(c.Value, "|") {
-		title = strings.TrimSpace(title)
-		if title != "" {
-			favs[title] = true
-		}
-	}
-	return favs
-}
 
-func saveFavorites(w http.ResponseWriter, favs map[string]bool) {
-	titles := make([]string, 0, len(favs))
-	for title := range favs {
-		titles = append(titles, title)
-	}
-	sort.Strings(titles)
+	id := newSessionID()
 	http.SetCookie(w, &http.Cookie{
-		Name:     cookieName,
-		Value:    strings.Join(titles, "|"),
+		Name:     sessionCookieName,
+		Value:    id,
 		Path:     "/",
-		MaxAge:   int((30 * 24 * time.Hour).Seconds()),
+		MaxAge:   int((365 * 24 * time.Hour).Seconds()),
 		HttpOnly: true,
 	})
+	return id
+}
+
+func newSessionID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// Une ID non aléatoire vaut mieux qu'un crash : très improbable avec crypto/rand.
+		return fmt.Sprintf("fallback-%d", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("%x", buf)
 }
 
-func render(w http.ResponseWriter, contentTemplate string, data interface{}) {
+func render(w http.ResponseWriter, r *http.Request, contentTemplate string, data interface{}) {
 	tmpl, err := template.ParseFiles(
 		"template/accueil.html",
 		"template/"+contentTemplate,
 	)
 	if err != nil {
-		serverError(w, err)
+		serverError(w, r, err)
 		return
 	}
 	if err := tmpl.ExecuteTemplate(w, "layout", data); err != nil {
-		serverError(w, err)
+		serverError(w, r, err)
 	}
 }
 
-func serverError(w http.ResponseWriter, err error) {
-	log.Printf("server error: %v", err)
+func serverError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("request %s: server error: %v", reqctx.FromContext(r.Context()), err)
 	http.Error(w, "Une erreur est survenue", http.StatusInternalServerError)
 }
 
@@ -436,5 +681,18 @@ func categoryLabel(raw string) string {
 	if raw == "" {
 		return ""
 	}
+	if strings.ToLower(raw) == localCategory {
+		return "Local"
+	}
 	return strings.Title(strings.ToLower(raw))
 }
+
+// mediaURLFor turns an absolute path under localMediaDir into the URL it's
+// served at through the /media/ file server route.
+func mediaURLFor(absPath string) string {
+	rel, err := filepath.Rel(localMediaDir, absPath)
+	if err != nil {
+		return ""
+	}
+	return "/media/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// matchEvent is a single SSE payload pushed to subscribed clients.
+type matchEvent struct {
+	Type  string `json:"type"` // "new", "thumbnail", "embed" or "score"
+	Match Match  `json:"match"`
+}
+
+// liveHub fans out match events to every subscribed client.
+type liveHub struct {
+	mu      sync.RWMutex
+	clients map[chan matchEvent]struct{}
+}
+
+func newLiveHub() *liveHub {
+	return &liveHub{clients: make(map[chan matchEvent]struct{})}
+}
+
+var hub = newLiveHub()
+
+func (h *liveHub) subscribe() chan matchEvent {
+	ch := make(chan matchEvent, 8)
+	h.mu.Lock()
+	h.clients[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *liveHub) unsubscribe(ch chan matchEvent) {
+	h.mu.Lock()
+	delete(h.clients, ch)
+	h.mu.Unlock()
+	close(ch)
+}
+
+func (h *liveHub) broadcast(ev matchEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.clients {
+		select {
+		case ch <- ev:
+		default:
+			// Le client est trop lent, on laisse tomber l'événement plutôt que de bloquer.
+		}
+	}
+}
+
+// LiveFeedHandler serves /events: a Server-Sent Events stream of live match updates.
+func LiveFeedHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming non supporté", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := hub.subscribe()
+	defer hub.unsubscribe(ch)
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, open := <-ch:
+			if !open {
+				return
+			}
+			payload, err := json.Marshal(ev)
+			if err != nil {
+				log.Printf("livefeed: échec d'encodage de l'événement: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, payload)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// StartLiveFeedPoller polls fetchMatches on the given interval and broadcasts a
+// diff against the previous snapshot (keyed by title+date) to every subscriber.
+// It is meant to be started once, in a background goroutine, from main.
+func StartLiveFeedPoller(interval time.Duration) {
+	go func() {
+		var previous []Match
+		for {
+			matches, err := fetchMatches("")
+			if err != nil {
+				log.Printf("livefeed: échec du polling ScoreBat: %v", err)
+				time.Sleep(interval)
+				continue
+			}
+			for _, ev := range diffMatches(previous, matches) {
+				hub.broadcast(ev)
+			}
+			previous = matches
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func matchKey(m Match) string {
+	return m.Title + "|" + m.DateRaw
+}
+
+// diffMatches compares two snapshots by title+date and reports new matches as
+// well as thumbnail/embed/score changes on matches present in both.
+func diffMatches(previous, current []Match) []matchEvent {
+	if previous == nil {
+		return nil
+	}
+
+	old := make(map[string]Match, len(previous))
+	for _, m := range previous {
+		old[matchKey(m)] = m
+	}
+
+	var events []matchEvent
+	for _, m := range current {
+		prev, existed := old[matchKey(m)]
+		if !existed {
+			events = append(events, matchEvent{Type: "new", Match: m})
+			continue
+		}
+		if prev.Thumbnail != m.Thumbnail {
+			events = append(events, matchEvent{Type: "thumbnail", Match: m})
+		}
+		if m.Score != "" && prev.Score != m.Score {
+			events = append(events, matchEvent{Type: "score", Match: m})
+		}
+		if len(prev.Videos) != len(m.Videos) {
+			events = append(events, matchEvent{Type: "embed", Match: m})
+		} else {
+			for i := range m.Videos {
+				if m.Videos[i].Embed != prev.Videos[i].Embed {
+					events = append(events, matchEvent{Type: "embed", Match: m})
+					break
+				}
+			}
+		}
+	}
+	return events
+}
@@ -0,0 +1,81 @@
+package controller
+
+import "testing"
+
+func TestDiffMatchesReportsNilPreviousAsNoEvents(t *testing.T) {
+	current := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01"}}
+
+	if events := diffMatches(nil, current); events != nil {
+		t.Fatalf("want no events against a nil previous snapshot, got %+v", events)
+	}
+}
+
+func TestDiffMatchesReportsNewMatch(t *testing.T) {
+	previous := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01"}}
+	current := []Match{
+		{Title: "PSG 3-1 OM", DateRaw: "2026-01-01"},
+		{Title: "Lyon 0-0 Nice", DateRaw: "2026-01-02"},
+	}
+
+	events := diffMatches(previous, current)
+
+	if len(events) != 1 || events[0].Type != "new" || events[0].Match.Title != "Lyon 0-0 Nice" {
+		t.Fatalf("want a single new event for Lyon 0-0 Nice, got %+v", events)
+	}
+}
+
+func TestDiffMatchesReportsThumbnailChange(t *testing.T) {
+	previous := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Thumbnail: "old.jpg"}}
+	current := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Thumbnail: "new.jpg"}}
+
+	events := diffMatches(previous, current)
+
+	if len(events) != 1 || events[0].Type != "thumbnail" {
+		t.Fatalf("want a single thumbnail event, got %+v", events)
+	}
+}
+
+func TestDiffMatchesReportsEmbedChange(t *testing.T) {
+	previous := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Videos: []struct {
+		Title string `json:"title"`
+		Embed string `json:"embed"`
+	}{{Title: "Highlights", Embed: "old-embed"}}}}
+	current := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Videos: []struct {
+		Title string `json:"title"`
+		Embed string `json:"embed"`
+	}{{Title: "Highlights", Embed: "new-embed"}}}}
+
+	events := diffMatches(previous, current)
+
+	if len(events) != 1 || events[0].Type != "embed" {
+		t.Fatalf("want a single embed event, got %+v", events)
+	}
+}
+
+func TestDiffMatchesReportsScoreChange(t *testing.T) {
+	previous := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Score: "2-1"}}
+	current := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Score: "3-1"}}
+
+	events := diffMatches(previous, current)
+
+	if len(events) != 1 || events[0].Type != "score" || events[0].Match.Score != "3-1" {
+		t.Fatalf("want a single score event carrying the new score, got %+v", events)
+	}
+}
+
+func TestDiffMatchesIgnoresUnknownScore(t *testing.T) {
+	previous := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Score: "2-1"}}
+	current := []Match{{Title: "PSG 3-1 OM", DateRaw: "2026-01-01"}}
+
+	if events := diffMatches(previous, current); events != nil {
+		t.Fatalf("want no score event when the new snapshot doesn't carry a score, got %+v", events)
+	}
+}
+
+func TestDiffMatchesReportsNoEventsWhenUnchanged(t *testing.T) {
+	m := Match{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Thumbnail: "thumb.jpg"}
+
+	if events := diffMatches([]Match{m}, []Match{m}); events != nil {
+		t.Fatalf("want no events for an unchanged match, got %+v", events)
+	}
+}
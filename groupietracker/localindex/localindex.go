@@ -0,0 +1,151 @@
+// Package localindex lets self-hosters overlay locally archived clips and
+// thumbnails on top of the matches fetched from remote providers, without
+// editing code: drop files in a directory and they show up fuzzy-matched
+// against Match.Title.
+package localindex
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+var videoExts = map[string]bool{".mp4": true, ".webm": true}
+var imageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true}
+
+// Entry is a local file matched against a ScoreBat match title.
+type Entry struct {
+	Path    string // absolute path, served under /media/
+	IsVideo bool
+}
+
+// Index is an in-memory, fuzzy-matched directory → title index, kept in
+// sync with the filesystem via fsnotify.
+type Index struct {
+	dir string
+
+	mu      sync.RWMutex
+	entries []Entry // rebuilt wholesale on every change; the dir is small enough in practice
+}
+
+// New builds an Index over dir, does an initial walk, and starts watching
+// dir for changes in the background. The returned Index is ready to use
+// immediately (possibly empty until the initial walk finishes).
+func New(dir string) (*Index, error) {
+	idx := &Index{dir: dir}
+	if err := idx.reindex(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	go idx.watch(watcher)
+	return idx, nil
+}
+
+func (idx *Index) watch(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) != 0 {
+				if err := idx.reindex(); err != nil {
+					log.Printf("localindex: échec de réindexation: %v", err)
+				}
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("localindex: erreur du watcher: %v", err)
+		}
+	}
+}
+
+func (idx *Index) reindex() error {
+	var entries []Entry
+	err := filepath.WalkDir(idx.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		switch {
+		case videoExts[ext]:
+			entries = append(entries, Entry{Path: path, IsVideo: true})
+		case imageExts[ext]:
+			entries = append(entries, Entry{Path: path, IsVideo: false})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	idx.entries = entries
+	idx.mu.Unlock()
+	log.Printf("localindex: %d fichier(s) indexé(s) sous %s", len(entries), idx.dir)
+	return nil
+}
+
+// Match fuzzy-matches title against indexed filenames: a hit is a file
+// whose basename (minus extension, normalized) contains every word of the
+// title, or vice versa. It returns the best video and thumbnail found, if
+// any.
+func (idx *Index) Match(title string) (video *Entry, thumbnail *Entry) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	needle := normalize(title)
+	if needle == "" {
+		return nil, nil
+	}
+
+	for i := range idx.entries {
+		e := idx.entries[i]
+		name := normalize(strings.TrimSuffix(filepath.Base(e.Path), filepath.Ext(e.Path)))
+		if !fuzzyContains(name, needle) {
+			continue
+		}
+		if e.IsVideo && video == nil {
+			video = &e
+		}
+		if !e.IsVideo && thumbnail == nil {
+			thumbnail = &e
+		}
+	}
+	return video, thumbnail
+}
+
+func normalize(s string) string {
+	s = strings.ToLower(s)
+	s = strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9')
+	}), " ")
+	return s
+}
+
+// fuzzyContains reports whether every word of needle appears somewhere in
+// haystack, regardless of order.
+func fuzzyContains(haystack, needle string) bool {
+	for _, word := range strings.Fields(needle) {
+		if len(word) < 3 || !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
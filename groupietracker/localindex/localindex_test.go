@@ -0,0 +1,29 @@
+package localindex
+
+import "testing"
+
+func TestNormalizeLowercasesAndCollapsesPunctuation(t *testing.T) {
+	got := normalize("PSG_3-1__OM (Highlights).mp4")
+	want := "psg 3 1 om highlights mp4"
+	if got != want {
+		t.Fatalf("normalize() = %q, want %q", got, want)
+	}
+}
+
+func TestFuzzyContainsMatchesRegardlessOfWordOrder(t *testing.T) {
+	cases := []struct {
+		haystack, needle string
+		want             bool
+	}{
+		{"psg marseille highlights", "psg marseille highlights", true},
+		{"marseille vs psg full replay", "psg marseille highlights", false}, // "highlights" missing
+		{"highlights marseille psg", "psg marseille highlights", true},
+		{"psg marseille", "ab", false}, // words shorter than 3 chars never match
+	}
+
+	for _, c := range cases {
+		if got := fuzzyContains(c.haystack, c.needle); got != c.want {
+			t.Errorf("fuzzyContains(%q, %q) = %v, want %v", c.haystack, c.needle, got, c.want)
+		}
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
+	"groupietracker/controller"
 	"groupietracker/router"
 )
 
 func main() {
 	r := router.SetupRoutes()
 
+	controller.StartLiveFeedPoller(30 * time.Second)
+
 	fmt.Println("http://localhost:8080")
 
 	if err := http.ListenAndServe(":8080", r); err != nil {
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FixtureProvider serves matches from a local JSON file, using the same
+// shape as the ScoreBat API response. It is meant for offline development
+// and as a source that keeps working when every remote provider is down.
+type FixtureProvider struct {
+	name string
+	path string
+}
+
+// NewFixtureProvider builds a FixtureProvider named name, reading matches
+// from the JSON file at path on every Fetch (the Aggregator applies its own
+// cache TTL on top).
+func NewFixtureProvider(name, path string) *FixtureProvider {
+	return &FixtureProvider{name: name, path: path}
+}
+
+func (p *FixtureProvider) Name() string { return p.name }
+
+func (p *FixtureProvider) Fetch(ctx context.Context) ([]Match, error) {
+	raw, err := os.ReadFile(p.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fixture %s: %w", p.path, err)
+	}
+
+	var matches []Match
+	if err := json.Unmarshal(raw, &matches); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", p.path, err)
+	}
+	return matches, nil
+}
@@ -0,0 +1,202 @@
+// Package provider defines the Provider interface used to fetch matches from
+// one or more upstream sources (ScoreBat, other APIs, scrapers, local
+// fixtures) and an Aggregator that merges them into a single deduplicated
+// list.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Video is an embeddable video attached to a Match.
+type Video struct {
+	Title string `json:"title"`
+	Embed string `json:"embed"`
+}
+
+// Match is the provider-neutral shape every source normalizes into.
+type Match struct {
+	Title        string
+	Competition  string
+	MatchviewURL string
+	Thumbnail    string
+	DateRaw      string
+	Score        string // e.g. "3-1"; empty when the score isn't known yet
+	Videos       []Video
+	Source       string
+}
+
+// Provider is a single upstream source of matches.
+type Provider interface {
+	// Name identifies the provider, used for the ?source= filter, logs, and
+	// the circuit breaker.
+	Name() string
+	// Fetch returns the provider's current matches.
+	Fetch(ctx context.Context) ([]Match, error)
+}
+
+// providerState tracks the per-provider cache and circuit breaker.
+type providerState struct {
+	mu sync.Mutex
+
+	cachedAt  time.Time
+	cached    []Match
+	lastErr   error
+	failures  int
+	openUntil time.Time
+}
+
+const (
+	maxFailuresBeforeOpen = 3
+	breakerCooldown       = time.Minute
+)
+
+// Aggregator concurrently queries every registered Provider, merges their
+// results, and deduplicates matches by normalized title+date. A provider
+// that keeps failing is skipped ("circuit opened") for breakerCooldown
+// instead of being retried on every request.
+type Aggregator struct {
+	providers []Provider
+	cacheTTL  time.Duration
+
+	statesMu sync.Mutex
+	states   map[string]*providerState
+}
+
+// NewAggregator builds an Aggregator over the given providers, each cached
+// independently for cacheTTL.
+func NewAggregator(cacheTTL time.Duration, providers ...Provider) *Aggregator {
+	states := make(map[string]*providerState, len(providers))
+	for _, p := range providers {
+		states[p.Name()] = &providerState{}
+	}
+	return &Aggregator{providers: providers, cacheTTL: cacheTTL, states: states}
+}
+
+// Fetch queries every provider concurrently (respecting each provider's own
+// cache and circuit breaker), merges their matches, and deduplicates by
+// normalized title+date, merging videos from duplicate entries into one.
+func (a *Aggregator) Fetch(ctx context.Context, source string) ([]Match, error) {
+	var wg sync.WaitGroup
+	results := make([][]Match, len(a.providers))
+	errs := make([]error, len(a.providers))
+	attempted := false
+
+	for i, p := range a.providers {
+		if source != "" && p.Name() != source {
+			continue
+		}
+		attempted = true
+		wg.Add(1)
+		go func(i int, p Provider) {
+			defer wg.Done()
+			matches, err := a.fetchProvider(ctx, p)
+			errs[i] = err
+			if err != nil {
+				log.Printf("provider %s: %v", p.Name(), err)
+				if len(matches) == 0 {
+					return
+				}
+				// fetchProvider still hands back its last good cache on
+				// failure: serve that stale data instead of dropping the
+				// provider entirely, so one dead provider degrades rather
+				// than disappears.
+				log.Printf("provider %s: service du cache périmé après échec", p.Name())
+			}
+			results[i] = matches
+		}(i, p)
+	}
+	wg.Wait()
+
+	var all []Match
+	var lastErr error
+	for i, r := range results {
+		all = append(all, r...)
+		if errs[i] != nil {
+			lastErr = errs[i]
+		}
+	}
+
+	// Every attempted provider failed and none had usable stale data: there
+	// is genuinely nothing to serve, so surface the failure instead of
+	// returning an empty list that callers can't tell apart from "no
+	// matches today".
+	if attempted && len(all) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("aucun provider disponible: %w", lastErr)
+	}
+
+	return dedupe(all), nil
+}
+
+func (a *Aggregator) fetchProvider(ctx context.Context, p Provider) ([]Match, error) {
+	state := a.states[p.Name()]
+	state.mu.Lock()
+
+	if time.Now().Before(state.openUntil) {
+		cached := state.cached
+		state.mu.Unlock()
+		return cached, state.lastErr
+	}
+	if time.Since(state.cachedAt) < a.cacheTTL && len(state.cached) > 0 {
+		cached := state.cached
+		state.mu.Unlock()
+		return cached, nil
+	}
+	state.mu.Unlock()
+
+	matches, err := p.Fetch(ctx)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if err != nil {
+		state.failures++
+		state.lastErr = err
+		if state.failures >= maxFailuresBeforeOpen {
+			state.openUntil = time.Now().Add(breakerCooldown)
+			log.Printf("provider %s: circuit ouvert pendant %s après %d échecs", p.Name(), breakerCooldown, state.failures)
+		}
+		return state.cached, err
+	}
+
+	state.failures = 0
+	state.openUntil = time.Time{}
+	for i := range matches {
+		matches[i].Source = p.Name()
+	}
+	state.cached = matches
+	state.cachedAt = time.Now()
+	return matches, nil
+}
+
+func dedupKey(m Match) string {
+	return m.Title + "|" + m.DateRaw
+}
+
+// dedupe merges matches that share a normalized title+date key, combining
+// their videos into a single entry instead of showing the same match twice.
+func dedupe(matches []Match) []Match {
+	order := make([]string, 0, len(matches))
+	merged := make(map[string]*Match, len(matches))
+
+	for _, m := range matches {
+		key := dedupKey(m)
+		existing, ok := merged[key]
+		if !ok {
+			mCopy := m
+			merged[key] = &mCopy
+			order = append(order, key)
+			continue
+		}
+		existing.Videos = append(existing.Videos, m.Videos...)
+	}
+
+	out := make([]Match, 0, len(order))
+	for _, key := range order {
+		out = append(out, *merged[key])
+	}
+	return out
+}
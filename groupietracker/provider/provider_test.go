@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type failingProvider struct {
+	name string
+	err  error
+}
+
+func (p failingProvider) Name() string { return p.name }
+
+func (p failingProvider) Fetch(ctx context.Context) ([]Match, error) {
+	return nil, p.err
+}
+
+func TestDedupeMergesVideosForSameTitleAndDate(t *testing.T) {
+	in := []Match{
+		{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Source: "scorebat", Videos: []Video{{Title: "Highlights", Embed: "a"}}},
+		{Title: "PSG 3-1 OM", DateRaw: "2026-01-01", Source: "fixtures", Videos: []Video{{Title: "Replay", Embed: "b"}}},
+		{Title: "Lyon 0-0 Nice", DateRaw: "2026-01-02", Source: "scorebat", Videos: []Video{{Title: "Highlights", Embed: "c"}}},
+	}
+
+	out := dedupe(in)
+
+	if len(out) != 2 {
+		t.Fatalf("want 2 deduplicated matches, got %d", len(out))
+	}
+	if out[0].Title != "PSG 3-1 OM" || len(out[0].Videos) != 2 {
+		t.Fatalf("want merged videos for the duplicate match, got %+v", out[0])
+	}
+	if out[1].Title != "Lyon 0-0 Nice" {
+		t.Fatalf("want second match untouched, got %+v", out[1])
+	}
+}
+
+func TestDedupePreservesFirstSeenOrder(t *testing.T) {
+	in := []Match{
+		{Title: "B", DateRaw: "2"},
+		{Title: "A", DateRaw: "1"},
+		{Title: "B", DateRaw: "2"},
+	}
+
+	out := dedupe(in)
+
+	if len(out) != 2 || out[0].Title != "B" || out[1].Title != "A" {
+		t.Fatalf("want order of first appearance, got %+v", out)
+	}
+}
+
+func TestAggregatorFetchReturnsErrorWhenEveryProviderFailsWithNoCache(t *testing.T) {
+	wantErr := errors.New("dns down")
+	a := NewAggregator(time.Minute, failingProvider{name: "scorebat", err: wantErr})
+
+	matches, err := a.Fetch(context.Background(), "")
+
+	if err == nil {
+		t.Fatal("want an error when no provider could serve anything")
+	}
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("want the underlying provider error wrapped, got %v", err)
+	}
+	if matches != nil {
+		t.Fatalf("want no matches alongside the error, got %+v", matches)
+	}
+}
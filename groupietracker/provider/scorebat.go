@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+const scoreBatURL = "https://www.scorebat.com/video-api/v3/"
+
+// scoreInTitle matches the score ScoreBat embeds in its match titles, e.g.
+// "Paris SG 3-1 Marseille" -> "3-1". ScoreBat's API has no separate score
+// field, so this is the only place the score is available.
+var scoreInTitle = regexp.MustCompile(`(\d+)\s*-\s*(\d+)`)
+
+func extractScore(title string) string {
+	m := scoreInTitle.FindStringSubmatch(title)
+	if m == nil {
+		return ""
+	}
+	return m[1] + "-" + m[2]
+}
+
+type scoreBatResponse struct {
+	Response []struct {
+		Title        string `json:"title"`
+		Competition  string `json:"competition"`
+		MatchviewURL string `json:"matchviewUrl"`
+		Thumbnail    string `json:"thumbnail"`
+		DateRaw      string `json:"date"`
+		Videos       []struct {
+			Title string `json:"title"`
+			Embed string `json:"embed"`
+		} `json:"videos"`
+	} `json:"response"`
+}
+
+// ScoreBatProvider fetches matches from the public ScoreBat video API.
+type ScoreBatProvider struct {
+	client *http.Client
+}
+
+// NewScoreBatProvider builds a ScoreBatProvider using the given client.
+func NewScoreBatProvider(client *http.Client) *ScoreBatProvider {
+	return &ScoreBatProvider{client: client}
+}
+
+func (p *ScoreBatProvider) Name() string { return "scorebat" }
+
+func (p *ScoreBatProvider) Fetch(ctx context.Context) ([]Match, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scoreBatURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scorebat: statut inattendu %d", resp.StatusCode)
+	}
+
+	var apiResp scoreBatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(apiResp.Response))
+	for _, m := range apiResp.Response {
+		videos := make([]Video, 0, len(m.Videos))
+		for _, v := range m.Videos {
+			videos = append(videos, Video{Title: v.Title, Embed: v.Embed})
+		}
+		matches = append(matches, Match{
+			Title:        m.Title,
+			Competition:  m.Competition,
+			MatchviewURL: m.MatchviewURL,
+			Thumbnail:    m.Thumbnail,
+			DateRaw:      m.DateRaw,
+			Score:        extractScore(m.Title),
+			Videos:       videos,
+		})
+	}
+	return matches, nil
+}
@@ -0,0 +1,17 @@
+package provider
+
+import "testing"
+
+func TestExtractScoreFindsEmbeddedScore(t *testing.T) {
+	got := extractScore("Paris SG 3-1 Marseille")
+	if got != "3-1" {
+		t.Fatalf("extractScore() = %q, want %q", got, "3-1")
+	}
+}
+
+func TestExtractScoreReturnsEmptyWithoutScore(t *testing.T) {
+	got := extractScore("Paris SG vs Marseille - Highlights")
+	if got != "" {
+		t.Fatalf("extractScore() = %q, want empty string", got)
+	}
+}
@@ -0,0 +1,21 @@
+// Package reqctx carries the per-request ID injected by router's middleware
+// chain through context.Context, so other packages (controller) can log it
+// without importing router (which imports them).
+package reqctx
+
+import "context"
+
+type key int
+
+const requestIDKey key = 0
+
+// WithRequestID returns a context carrying id, retrievable with FromContext.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
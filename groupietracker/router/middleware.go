@@ -0,0 +1,280 @@
+package router
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"groupietracker/reqctx"
+)
+
+// Middleware wraps an http.Handler with cross-cutting behavior. A chain is
+// applied left-to-right: chain(a, b, c)(h) runs a, then b, then c, then h.
+type Middleware func(http.Handler) http.Handler
+
+func chain(mws ...Middleware) Middleware {
+	return func(final http.Handler) http.Handler {
+		h := final
+		for i := len(mws) - 1; i >= 0; i-- {
+			h = mws[i](h)
+		}
+		return h
+	}
+}
+
+const requestIDHeader = "X-Request-ID"
+
+// withRequestID generates a UUID per request, stores it in the request
+// context via reqctx, and echoes it back on the response so operators can
+// correlate a client-reported failure with server logs.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(reqctx.WithRequestID(r.Context(), id)))
+	})
+}
+
+func newRequestID() string {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return fmt.Sprintf("fallback-%d", time.Now().UnixNano())
+	}
+	// Version 4 UUID layout.
+	buf[6] = (buf[6] & 0x0f) | 0x40
+	buf[8] = (buf[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16])
+}
+
+// gzipResponseWriter wraps http.ResponseWriter, transparently compressing
+// everything written to it.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.gz.Write(b)
+}
+
+// Flush is not promoted automatically because http.ResponseWriter is an
+// interface field: without this, wrapping a handler that relies on
+// http.Flusher (the SSE feed in controller/livefeed.go) would silently
+// break it. It flushes the gzip writer first so buffered bytes actually
+// reach the wire before the underlying flush.
+func (w *gzipResponseWriter) Flush() {
+	w.gz.Flush()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// withGzip compresses the response body when the client advertises support
+// for it via Accept-Encoding.
+func withGzip(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+
+		next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, gz: gz}, r)
+	})
+}
+
+// tokenBucket is a per-IP rate limiter: ratePerSecond tokens are added per
+// second, up to burst, and each request consumes one.
+type tokenBucket struct {
+	mu            sync.Mutex
+	buckets       map[string]*bucketState
+	ratePerSecond float64
+	burst         float64
+}
+
+type bucketState struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// bucketTTL is how long a client's bucket survives without a request before
+// it's evicted, so an attacker who churns connections (or just a site with
+// many one-off visitors) can't grow tb.buckets without bound.
+const bucketTTL = 10 * time.Minute
+
+func newTokenBucket(ratePerSecond float64, burst float64) *tokenBucket {
+	tb := &tokenBucket{
+		buckets:       make(map[string]*bucketState),
+		ratePerSecond: ratePerSecond,
+		burst:         burst,
+	}
+	go tb.evictLoop()
+	return tb
+}
+
+func (tb *tokenBucket) evictLoop() {
+	ticker := time.NewTicker(bucketTTL)
+	defer ticker.Stop()
+	for range ticker.C {
+		tb.evict(time.Now())
+	}
+}
+
+// evict drops buckets that haven't been touched in bucketTTL, relative to now.
+func (tb *tokenBucket) evict(now time.Time) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	for key, b := range tb.buckets {
+		if now.Sub(b.lastSeen) >= bucketTTL {
+			delete(tb.buckets, key)
+		}
+	}
+}
+
+func (tb *tokenBucket) allow(key string) bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := time.Now()
+	b, ok := tb.buckets[key]
+	if !ok {
+		b = &bucketState{tokens: tb.burst, lastSeen: now}
+		tb.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.lastSeen = now
+	b.tokens += elapsed * tb.ratePerSecond
+	if b.tokens > tb.burst {
+		b.tokens = tb.burst
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// withRateLimit rejects requests past ratePerSecond (with burst headroom)
+// per client IP with 429. This matters most for the cheap-to-abuse
+// endpoints like /fav-toggle and /search.
+func withRateLimit(ratePerSecond, burst float64) Middleware {
+	tb := newTokenBucket(ratePerSecond, burst)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientIP(r)
+			if !tb.allow(ip) {
+				http.Error(w, "trop de requêtes, réessayez plus tard", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP extracts the bare IP used as the rate limiter's bucket key.
+// r.RemoteAddr carries the ephemeral source port ("1.2.3.4:56789"), which
+// would key the bucket per-connection instead of per-client, so the port is
+// stripped before use.
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		return strings.TrimSpace(strings.Split(fwd, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// withRecover turns a panicking handler into a templated 500 instead of
+// crashing the server, logging the request ID so the stack trace can be
+// correlated with the client-visible failure.
+func withRecover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("request %s: panic récupéré: %v", reqctx.FromContext(r.Context()), rec)
+				http.Error(w, "Une erreur est survenue", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder captures the status code and byte count written, since
+// http.ResponseWriter doesn't expose either after the fact.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Flush is not promoted automatically because http.ResponseWriter is an
+// interface field: without this, wrapping a handler that relies on
+// http.Flusher (the SSE feed in controller/livefeed.go) would silently
+// break it.
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type accessLogEntry struct {
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Status     int     `json:"status"`
+	Bytes      int     `json:"bytes"`
+	DurationMS float64 `json:"durationMs"`
+	RequestID  string  `json:"requestId"`
+}
+
+// withAccessLog logs one structured JSON line per request: method, path,
+// status, bytes written, duration, and request ID.
+func withAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		entry := accessLogEntry{
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Status:     rec.status,
+			Bytes:      rec.bytes,
+			DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+			RequestID:  reqctx.FromContext(r.Context()),
+		}
+		if raw, err := json.Marshal(entry); err == nil {
+			log.Println(string(raw))
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllowsUpToBurstThenRejects(t *testing.T) {
+	tb := newTokenBucket(1, 3)
+
+	for i := 0; i < 3; i++ {
+		if !tb.allow("1.2.3.4") {
+			t.Fatalf("request %d: want allowed within burst", i)
+		}
+	}
+	if tb.allow("1.2.3.4") {
+		t.Fatal("want rejected once burst is exhausted")
+	}
+}
+
+func TestTokenBucketTracksClientsIndependently(t *testing.T) {
+	tb := newTokenBucket(1, 1)
+
+	if !tb.allow("a") {
+		t.Fatal("want first client's first request allowed")
+	}
+	if !tb.allow("b") {
+		t.Fatal("want a different client to have its own bucket")
+	}
+	if tb.allow("a") {
+		t.Fatal("want first client's burst already spent")
+	}
+}
+
+func TestTokenBucketEvictsStaleEntries(t *testing.T) {
+	tb := newTokenBucket(1, 1)
+	tb.allow("a")
+	tb.allow("b")
+
+	tb.buckets["a"].lastSeen = time.Now().Add(-2 * bucketTTL)
+
+	tb.evict(time.Now())
+
+	if _, ok := tb.buckets["a"]; ok {
+		t.Fatal("want stale entry evicted")
+	}
+	if _, ok := tb.buckets["b"]; !ok {
+		t.Fatal("want fresh entry kept")
+	}
+}
+
+func TestClientIPStripsPort(t *testing.T) {
+	r := &http.Request{RemoteAddr: "1.2.3.4:56789"}
+	if got := clientIP(r); got != "1.2.3.4" {
+		t.Fatalf("clientIP() = %q, want %q", got, "1.2.3.4")
+	}
+}
+
+func TestClientIPPrefersForwardedFor(t *testing.T) {
+	r := &http.Request{RemoteAddr: "1.2.3.4:56789", Header: http.Header{}}
+	r.Header.Set("X-Forwarded-For", "5.6.7.8, 9.9.9.9")
+	if got := clientIP(r); got != "5.6.7.8" {
+		t.Fatalf("clientIP() = %q, want %q", got, "5.6.7.8")
+	}
+}
+
+func TestClientIPFallsBackToRawRemoteAddr(t *testing.T) {
+	r := &http.Request{RemoteAddr: "not-a-valid-addr"}
+	if got := clientIP(r); got != "not-a-valid-addr" {
+		t.Fatalf("clientIP() = %q, want %q", got, "not-a-valid-addr")
+	}
+}
@@ -3,25 +3,44 @@ package router
 import (
 	"net/http"
 
+	"groupietracker/apictrl"
 	"groupietracker/controller"
 )
 
-func SetupRoutes() *http.ServeMux {
+func SetupRoutes() http.Handler {
 	mux := http.NewServeMux()
 
 	
 	fs := http.FileServer(http.Dir("static"))
 	mux.Handle("/static/", http.StripPrefix("/static/", fs))
 
+	media := http.FileServer(http.Dir("data/local-media"))
+	mux.Handle("/media/", http.StripPrefix("/media/", media)) // Overlay local (voir localindex)
+
 	
 	mux.HandleFunc("/", controller.HomeHandler)
 	mux.HandleFunc("/matches", controller.CollectionHandler)        // Page Principale/Recherche/Catégorie
 	mux.HandleFunc("/match", controller.DetailHandler)              // Page Détail
 	mux.HandleFunc("/favorites", controller.FavoritesPageHandler)   // Page Favoris
 	mux.HandleFunc("/fav-toggle", controller.ToggleFavoriteHandler) // Action de favoris
+	mux.HandleFunc("/favorites/export", controller.ExportFavoritesHandler)
+	mux.HandleFunc("/favorites/import", controller.ImportFavoritesHandler)
+	mux.HandleFunc("/favorites/recovery", controller.SetRecoveryPassphraseHandler) // Définit la passphrase de récupération
+	mux.HandleFunc("/favorites/recover", controller.RecoverFavoritesHandler)       // Récupère les favoris via la passphrase
 	mux.HandleFunc("/search", controller.SearchResultsHandler)      // Page Résultats
 	mux.HandleFunc("/category", controller.CategoryHandler)         // Page Catégorie
-	mux.HandleFunc("/about", controller.AboutHandler)               // Page À propos 
+	mux.HandleFunc("/about", controller.AboutHandler)               // Page À propos
+	mux.HandleFunc("/events", controller.LiveFeedHandler)           // Flux SSE des mises à jour live
+
+	apictrl.RegisterRoutes(mux)
+
+	handler := chain(
+		withRequestID,
+		withRecover,
+		withAccessLog,
+		withGzip,
+		withRateLimit(5, 20),
+	)(mux)
 
-	return mux
+	return handler
 }
@@ -0,0 +1,193 @@
+// Package store provides pluggable persistence for user data, starting with
+// favorites. The JSON-file implementation needs no external dependency and
+// keeps one file per process, mirroring how the rest of groupietracker avoids
+// third-party packages for simple needs.
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Favorite is a single favorited match, stamped with when it was added.
+type Favorite struct {
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// FavoritesStore persists each user's favorite matches, keyed by an opaque
+// user ID, and supports bulk export/import for backup or device transfer, as
+// well as passphrase-based recovery for when a user loses their session
+// cookie (the anonymous session ID is otherwise unrecoverable).
+type FavoritesStore interface {
+	List(userID string) ([]Favorite, error)
+	Add(userID, title string) error
+	Remove(userID, title string) error
+	Export(userID string) ([]byte, error)
+	Import(userID string, data []byte) error
+
+	// SetRecoveryPassphrase attaches a recovery passphrase to userID,
+	// overwriting any passphrase previously set for that user.
+	SetRecoveryPassphrase(userID, passphrase string) error
+	// Recover returns the userID previously attached to passphrase.
+	Recover(passphrase string) (string, error)
+}
+
+// storeFile is the on-disk shape of the favorites file: favorites keyed by
+// userID, plus an index mapping a hashed recovery passphrase back to the
+// userID that set it.
+type storeFile struct {
+	Favorites map[string]map[string]time.Time `json:"favorites"`
+	Recovery  map[string]string               `json:"recovery"` // sha256(passphrase) hex -> userID
+}
+
+// JSONFavoritesStore is a FavoritesStore backed by a single JSON file on disk.
+type JSONFavoritesStore struct {
+	path string
+
+	mu       sync.Mutex
+	data     map[string]map[string]time.Time // userID -> title -> createdAt
+	recovery map[string]string               // sha256(passphrase) hex -> userID
+}
+
+// NewJSONFavoritesStore loads (or creates) the favorites file at path.
+func NewJSONFavoritesStore(path string) (*JSONFavoritesStore, error) {
+	s := &JSONFavoritesStore{
+		path:     path,
+		data:     make(map[string]map[string]time.Time),
+		recovery: make(map[string]string),
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("store: lecture de %s: %w", path, err)
+	}
+	if len(raw) == 0 {
+		return s, nil
+	}
+	var file storeFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("store: parsing de %s: %w", path, err)
+	}
+	if file.Favorites != nil {
+		s.data = file.Favorites
+	}
+	if file.Recovery != nil {
+		s.recovery = file.Recovery
+	}
+	return s, nil
+}
+
+func (s *JSONFavoritesStore) List(userID string) ([]Favorite, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	favs := make([]Favorite, 0, len(s.data[userID]))
+	for title, createdAt := range s.data[userID] {
+		favs = append(favs, Favorite{Title: title, CreatedAt: createdAt})
+	}
+	return favs, nil
+}
+
+func (s *JSONFavoritesStore) Add(userID, title string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[userID] == nil {
+		s.data[userID] = make(map[string]time.Time)
+	}
+	if _, exists := s.data[userID][title]; !exists {
+		s.data[userID][title] = time.Now()
+	}
+	return s.saveLocked()
+}
+
+func (s *JSONFavoritesStore) Remove(userID, title string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data[userID], title)
+	return s.saveLocked()
+}
+
+func (s *JSONFavoritesStore) Export(userID string) ([]byte, error) {
+	favs, _ := s.List(userID)
+	return json.MarshalIndent(favs, "", "  ")
+}
+
+func (s *JSONFavoritesStore) Import(userID string, raw []byte) error {
+	var favs []Favorite
+	if err := json.Unmarshal(raw, &favs); err != nil {
+		return fmt.Errorf("store: import invalide: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[userID] == nil {
+		s.data[userID] = make(map[string]time.Time)
+	}
+	for _, f := range favs {
+		if f.Title == "" {
+			continue
+		}
+		if f.CreatedAt.IsZero() {
+			f.CreatedAt = time.Now()
+		}
+		s.data[userID][f.Title] = f.CreatedAt
+	}
+	return s.saveLocked()
+}
+
+// SetRecoveryPassphrase attaches a recovery passphrase to userID. Only the
+// passphrase's hash is persisted, never the passphrase itself.
+func (s *JSONFavoritesStore) SetRecoveryPassphrase(userID, passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("store: passphrase vide")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recovery[hashPassphrase(passphrase)] = userID
+	return s.saveLocked()
+}
+
+// Recover returns the userID previously attached to passphrase via
+// SetRecoveryPassphrase, so a user who lost their session cookie can
+// reattach to their existing favorites instead of starting over.
+func (s *JSONFavoritesStore) Recover(passphrase string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userID, ok := s.recovery[hashPassphrase(passphrase)]
+	if !ok {
+		return "", fmt.Errorf("store: passphrase inconnue")
+	}
+	return userID, nil
+}
+
+func hashPassphrase(passphrase string) string {
+	sum := sha256.Sum256([]byte(passphrase))
+	return hex.EncodeToString(sum[:])
+}
+
+// saveLocked writes the whole store to disk. Callers must hold s.mu.
+func (s *JSONFavoritesStore) saveLocked() error {
+	raw, err := json.MarshalIndent(storeFile{Favorites: s.data, Recovery: s.recovery}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("store: encodage: %w", err)
+	}
+	if err := os.WriteFile(s.path, raw, 0o644); err != nil {
+		return fmt.Errorf("store: écriture de %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestStore(t *testing.T) *JSONFavoritesStore {
+	t.Helper()
+	s, err := NewJSONFavoritesStore(filepath.Join(t.TempDir(), "favorites.json"))
+	if err != nil {
+		t.Fatalf("NewJSONFavoritesStore() error = %v", err)
+	}
+	return s
+}
+
+func TestAddThenRemoveRoundTrips(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.Add("u1", "PSG 3-1 OM"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	favs, err := s.List("u1")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(favs) != 1 || favs[0].Title != "PSG 3-1 OM" {
+		t.Fatalf("want one favorite after Add, got %+v", favs)
+	}
+
+	if err := s.Remove("u1", "PSG 3-1 OM"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	favs, _ = s.List("u1")
+	if len(favs) != 0 {
+		t.Fatalf("want no favorites after Remove, got %+v", favs)
+	}
+}
+
+func TestAddIsIdempotent(t *testing.T) {
+	s := newTestStore(t)
+
+	s.Add("u1", "PSG 3-1 OM")
+	s.Add("u1", "PSG 3-1 OM")
+
+	favs, _ := s.List("u1")
+	if len(favs) != 1 {
+		t.Fatalf("want adding the same title twice to be a no-op, got %+v", favs)
+	}
+}
+
+func TestAddKeepsUsersIndependent(t *testing.T) {
+	s := newTestStore(t)
+
+	s.Add("u1", "PSG 3-1 OM")
+	s.Add("u2", "Lyon 0-0 Nice")
+
+	favsU1, _ := s.List("u1")
+	favsU2, _ := s.List("u2")
+	if len(favsU1) != 1 || favsU1[0].Title != "PSG 3-1 OM" {
+		t.Fatalf("want u1 to only have its own favorite, got %+v", favsU1)
+	}
+	if len(favsU2) != 1 || favsU2[0].Title != "Lyon 0-0 Nice" {
+		t.Fatalf("want u2 to only have its own favorite, got %+v", favsU2)
+	}
+}
+
+func TestImportMergesWithoutDroppingExisting(t *testing.T) {
+	s := newTestStore(t)
+	s.Add("u1", "PSG 3-1 OM")
+
+	raw, err := s.Export("u1")
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	imported := []byte(`[{"title":"Lyon 0-0 Nice"}]`)
+	if err := s.Import("u1", imported); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	favs, _ := s.List("u1")
+	if len(favs) != 2 {
+		t.Fatalf("want the imported favorite merged alongside the existing one, got %+v", favs)
+	}
+
+	// Re-importing the original export must not duplicate or drop anything.
+	if err := s.Import("u1", raw); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	favs, _ = s.List("u1")
+	if len(favs) != 2 {
+		t.Fatalf("want re-importing the same export to be idempotent, got %+v", favs)
+	}
+}
+
+func TestImportRejectsInvalidJSON(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.Import("u1", []byte("not json")); err == nil {
+		t.Fatal("want an error for invalid import payload")
+	}
+}
+
+func TestRecoverReturnsUserIDForMatchingPassphrase(t *testing.T) {
+	s := newTestStore(t)
+	s.Add("u1", "PSG 3-1 OM")
+
+	if err := s.SetRecoveryPassphrase("u1", "correct horse battery staple"); err != nil {
+		t.Fatalf("SetRecoveryPassphrase() error = %v", err)
+	}
+
+	got, err := s.Recover("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if got != "u1" {
+		t.Fatalf("Recover() = %q, want %q", got, "u1")
+	}
+}
+
+func TestRecoverRejectsUnknownPassphrase(t *testing.T) {
+	s := newTestStore(t)
+
+	if _, err := s.Recover("never set"); err == nil {
+		t.Fatal("want an error for an unknown passphrase")
+	}
+}
+
+func TestSetRecoveryPassphraseRejectsEmpty(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.SetRecoveryPassphrase("u1", ""); err == nil {
+		t.Fatal("want an error for an empty passphrase")
+	}
+}
@@ -0,0 +1,195 @@
+// Package useragent rotates a realistic browser User-Agent across outbound
+// requests, so the ScoreBat client doesn't present Go's default UA (an easy
+// target for upstream rate-limiting/blocking).
+package useragent
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// statsURL points at a caniuse-style fulldata JSON exposing per-browser
+// usage shares, used to keep the pool's weights current.
+const statsURL = "https://raw.githubusercontent.com/Fyrd/caniuse/main/fulldata.json"
+
+const refreshInterval = 24 * time.Hour
+
+// entry is one candidate User-Agent and its relative weight.
+type entry struct {
+	ua     string
+	weight float64
+}
+
+// fallback is the bundled list used when the stats fetch fails or hasn't
+// run yet, so the client always has something plausible to send.
+var fallback = []entry{
+	{ua: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", weight: 0.45},
+	{ua: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", weight: 0.2},
+	{ua: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:126.0) Gecko/20100101 Firefox/126.0", weight: 0.15},
+	{ua: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7; rv:126.0) Gecko/20100101 Firefox/126.0", weight: 0.1},
+	{ua: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Edg/124.0.0.0 Safari/537.36", weight: 0.1},
+}
+
+// Policy decides which User-Agent (and matching Accept-Language/Sec-CH-UA
+// headers) to send on each outbound request. It refreshes its weighted
+// pool from statsURL roughly every 24h, in the background, and falls back
+// to the bundled list when the fetch fails or hasn't completed yet.
+type Policy struct {
+	client *http.Client
+
+	mu   sync.RWMutex
+	pool []entry
+}
+
+// NewPolicy builds a Policy seeded with the bundled fallback list and starts
+// its background refresh loop. client is used to fetch the stats JSON.
+func NewPolicy(client *http.Client) *Policy {
+	p := &Policy{client: client, pool: fallback}
+	go p.refreshLoop()
+	return p
+}
+
+func (p *Policy) refreshLoop() {
+	for {
+		p.refresh()
+		time.Sleep(refreshInterval)
+	}
+}
+
+func (p *Policy) refresh() {
+	pool, err := fetchPool(p.client)
+	if err != nil || len(pool) == 0 {
+		return
+	}
+	p.mu.Lock()
+	p.pool = pool
+	p.mu.Unlock()
+}
+
+// fetchPool fetches and weighs browser usage shares. The caniuse fulldata
+// payload is large and its schema drifts across versions, so this parses
+// only the top-level per-browser usage numbers it needs; any shape it
+// doesn't recognize makes it return an error and the caller keeps the
+// fallback list.
+func fetchPool(client *http.Client) ([]entry, error) {
+	resp, err := client.Get(statsURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Agents map[string]struct {
+			UsageGlobal float64 `json:"usage_global"`
+		} `json:"agents"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	var pool []entry
+	for name, agent := range payload.Agents {
+		ua, ok := templateFor(name)
+		if !ok || agent.UsageGlobal <= 0 {
+			continue
+		}
+		pool = append(pool, entry{ua: ua, weight: agent.UsageGlobal})
+	}
+	return pool, nil
+}
+
+// templateFor maps a caniuse agent key to a representative desktop UA
+// string, since the stats payload only tells us usage share per browser,
+// not a ready-to-send header value.
+func templateFor(name string) (string, bool) {
+	for _, f := range fallback {
+		switch name {
+		case "chrome":
+			if strings.Contains(f.ua, "Chrome/") && !strings.Contains(f.ua, "Edg/") {
+				return f.ua, true
+			}
+		case "firefox":
+			if strings.Contains(f.ua, "Firefox/") {
+				return f.ua, true
+			}
+		case "edge":
+			if strings.Contains(f.ua, "Edg/") {
+				return f.ua, true
+			}
+		}
+	}
+	return "", false
+}
+
+// pick returns a UA drawn at random, weighted by usage share.
+func (p *Policy) pick() string {
+	p.mu.RLock()
+	pool := p.pool
+	p.mu.RUnlock()
+
+	if len(pool) == 0 {
+		return fallback[0].ua
+	}
+
+	var total float64
+	for _, e := range pool {
+		total += e.weight
+	}
+	r := rand.Float64() * total
+	for _, e := range pool {
+		r -= e.weight
+		if r <= 0 {
+			return e.ua
+		}
+	}
+	return pool[len(pool)-1].ua
+}
+
+// headersFor derives Accept-Language and Sec-CH-UA headers that are
+// coherent with ua, so requests don't look like a UA string bolted onto an
+// otherwise default client.
+func headersFor(ua string) map[string]string {
+	headers := map[string]string{"Accept-Language": "en-US,en;q=0.9"}
+	if strings.Contains(ua, "Chrome/") || strings.Contains(ua, "Edg/") {
+		headers["Sec-CH-UA"] = `"Chromium";v="124", "Not-A.Brand";v="99"`
+	}
+	return headers
+}
+
+// roundTripper wraps an http.RoundTripper, stamping every outbound request
+// with a UA (and matching headers) picked from policy.
+type roundTripper struct {
+	policy *Policy
+	next   http.RoundTripper
+}
+
+// Wrap returns an http.RoundTripper that applies policy to every request
+// before delegating to next (http.DefaultTransport if next is nil).
+func Wrap(policy *Policy, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &roundTripper{policy: policy, next: next}
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ua := rt.policy.pick()
+
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", ua)
+	for k, v := range headersFor(ua) {
+		req.Header.Set(k, v)
+	}
+
+	return rt.next.RoundTrip(req)
+}
+
+// SetUserAgentPolicy pins client to always send ua, bypassing rotation, so
+// tests can assert on a stable header value.
+func SetUserAgentPolicy(client *http.Client, ua string) {
+	client.Transport = Wrap(&Policy{pool: []entry{{ua: ua, weight: 1}}}, client.Transport)
+}
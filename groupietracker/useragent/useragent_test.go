@@ -0,0 +1,39 @@
+package useragent
+
+import "testing"
+
+func TestPolicyPickReturnsOnlyPoolMembers(t *testing.T) {
+	p := &Policy{pool: []entry{
+		{ua: "alpha", weight: 1},
+		{ua: "beta", weight: 1},
+	}}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		seen[p.pick()] = true
+	}
+
+	for ua := range seen {
+		if ua != "alpha" && ua != "beta" {
+			t.Fatalf("pick() returned %q, not a pool member", ua)
+		}
+	}
+}
+
+func TestPolicyPickSingleEntryAlwaysWins(t *testing.T) {
+	p := &Policy{pool: []entry{{ua: "only-one", weight: 1}}}
+
+	for i := 0; i < 10; i++ {
+		if got := p.pick(); got != "only-one" {
+			t.Fatalf("pick() = %q, want %q", got, "only-one")
+		}
+	}
+}
+
+func TestPolicyPickFallsBackWhenPoolEmpty(t *testing.T) {
+	p := &Policy{}
+
+	if got := p.pick(); got != fallback[0].ua {
+		t.Fatalf("pick() = %q, want fallback[0].ua %q", got, fallback[0].ua)
+	}
+}